@@ -0,0 +1,69 @@
+package transition_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/transition"
+)
+
+func TestAroundWrapsTransitionAndReceivesArgs(t *testing.T) {
+	var trace []string
+
+	sm := transition.New()
+	sm.Initial("checkout")
+	sm.State("paid")
+
+	sm.Event("pay").To("paid").From("checkout").
+		Around(func(ctx context.Context, obj interface{}, next func() error) error {
+			trace = append(trace, "around:before")
+			err := next()
+			trace = append(trace, "around:after")
+			return err
+		}).
+		Before(func(ctx context.Context, obj interface{}, args ...interface{}) error {
+			amount := args[0].(int)
+			trace = append(trace, "before")
+			if amount <= 0 {
+				return errors.New("amount must be positive")
+			}
+			return nil
+		})
+
+	order := &Order{}
+	if err := sm.Trigger(context.TODO(), "pay", order, 100, "card"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"around:before", "before", "around:after"}
+	if len(trace) != len(want) {
+		t.Fatalf("expected trace %v, got %v", want, trace)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Errorf("expected trace %v, got %v", want, trace)
+			break
+		}
+	}
+}
+
+func TestAroundAbortsTransitionWithoutCallingNext(t *testing.T) {
+	sm := transition.New()
+	sm.Initial("checkout")
+	sm.State("paid")
+
+	sm.Event("pay").To("paid").From("checkout").
+		Around(func(ctx context.Context, obj interface{}, next func() error) error {
+			return nil // deliberately skips next
+		})
+
+	order := &Order{}
+	if err := sm.Trigger(context.TODO(), "pay", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if order.GetState() != "checkout" {
+		t.Errorf("expected state to remain checkout, got %s", order.GetState())
+	}
+}
@@ -0,0 +1,157 @@
+package transition
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrAsyncStopped is returned by Query once Run has exited, whether because
+// its ctx was cancelled or Stop was called, so callers don't block forever
+// sending to a loop that is no longer reading.
+var ErrAsyncStopped = errors.New("transition: async state machine has stopped")
+
+// FollowUpFunc is attached to a state via AsyncStateMachine.OnEnter. It runs
+// in the loop's goroutine right after the state is entered and, when
+// nextEvent is non-empty, that event is applied to the same object at once,
+// ahead of anything still queued — the mechanism for long-running states
+// that self-transition once their work completes.
+type FollowUpFunc func(ctx context.Context, obj interface{}) (nextEvent string, err error)
+
+type asyncJob struct {
+	ctx   context.Context
+	event string
+	obj   interface{}
+	args  []interface{}
+}
+
+type asyncQuery struct {
+	obj    interface{}
+	fn     func(obj interface{}) interface{}
+	result chan interface{}
+}
+
+// AsyncStateMachine wraps a StateMachine with a single worker goroutine
+// draining an event queue. Because every Schedule-d transition and every
+// Query is applied by that one goroutine, one at a time, triggers on the
+// same object (or any object) never race with each other.
+type AsyncStateMachine struct {
+	sm        *StateMachine
+	followUps map[string]FollowUpFunc
+
+	jobs    chan asyncJob
+	queries chan asyncQuery
+	done    chan struct{}
+}
+
+// NewAsyncStateMachine wraps sm. bufferSize sets how many scheduled events
+// may queue up before Schedule blocks.
+func NewAsyncStateMachine(sm *StateMachine, bufferSize int) *AsyncStateMachine {
+	return &AsyncStateMachine{
+		sm:        sm,
+		followUps: make(map[string]FollowUpFunc),
+		jobs:      make(chan asyncJob, bufferSize),
+		queries:   make(chan asyncQuery),
+		done:      make(chan struct{}),
+	}
+}
+
+// OnEnter registers fn to run whenever an event applied by Run's loop
+// leaves an object in state; calling Trigger on the wrapped StateMachine
+// directly does not run it. If fn returns a non-empty nextEvent, that
+// event is applied to the same object as soon as fn returns, before the
+// loop takes the next queued event.
+func (a *AsyncStateMachine) OnEnter(state string, fn FollowUpFunc) *AsyncStateMachine {
+	a.followUps[state] = fn
+	return a
+}
+
+// Schedule enqueues event to be applied to obj by Run's loop. It returns
+// once the event is queued, not once it has been applied.
+func (a *AsyncStateMachine) Schedule(ctx context.Context, event string, obj interface{}, args ...interface{}) {
+	a.jobs <- asyncJob{ctx: ctx, event: event, obj: obj, args: args}
+}
+
+// Query runs fn against obj from inside the loop's goroutine, so it can
+// never observe obj mid-transition, and returns fn's result. It returns
+// ErrAsyncStopped instead of blocking forever if Run has already exited.
+func (a *AsyncStateMachine) Query(obj interface{}, fn func(obj interface{}) interface{}) (interface{}, error) {
+	q := asyncQuery{obj: obj, fn: fn, result: make(chan interface{}, 1)}
+	select {
+	case a.queries <- q:
+	case <-a.done:
+		return nil, ErrAsyncStopped
+	}
+	select {
+	case result := <-q.result:
+		return result, nil
+	case <-a.done:
+		return nil, ErrAsyncStopped
+	}
+}
+
+// Run drains scheduled events and queries sequentially until ctx is
+// cancelled or Stop is called and the queue is empty.
+func (a *AsyncStateMachine) Run(ctx context.Context) {
+	defer close(a.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case q := <-a.queries:
+			q.result <- q.fn(q.obj)
+		case job, ok := <-a.jobs:
+			if !ok {
+				return
+			}
+			a.apply(ctx, job)
+		}
+	}
+}
+
+// apply triggers job's event and, as long as the resulting state has a
+// registered follow-up, keeps applying whatever event it returns, all
+// within this call rather than by re-enqueuing onto jobs: self-sending to
+// the channel this same goroutine is the sole reader of would deadlock a
+// full buffer, and could race a concurrent Stop closing it. runCtx is
+// Run's ctx, checked between hops so a cycle of follow-ups (A schedules B,
+// B schedules A, ...) can't loop forever and starve Run's select; job.ctx
+// is what's still passed to Trigger, since that's the caller's ctx for
+// this particular scheduled event.
+func (a *AsyncStateMachine) apply(runCtx context.Context, job asyncJob) {
+	ctx, event, obj, args := job.ctx, job.event, job.obj, job.args
+	for {
+		select {
+		case <-runCtx.Done():
+			return
+		default:
+		}
+
+		if err := a.sm.Trigger(ctx, event, obj, args...); err != nil {
+			return
+		}
+
+		stater, ok := obj.(Stater)
+		if !ok {
+			return
+		}
+
+		fn, ok := a.followUps[stater.GetState()]
+		if !ok {
+			return
+		}
+
+		nextEvent, err := fn(ctx, obj)
+		if err != nil || nextEvent == "" {
+			return
+		}
+
+		event, args = nextEvent, nil
+	}
+}
+
+// Stop closes the event queue and blocks until Run has drained it and
+// returned.
+func (a *AsyncStateMachine) Stop() {
+	close(a.jobs)
+	<-a.done
+}
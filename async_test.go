@@ -0,0 +1,176 @@
+package transition_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alextanhongpin/transition"
+)
+
+func getAsyncStateMachine() *transition.StateMachine {
+	sm := transition.New()
+
+	sm.Initial("pending")
+	sm.State("processing")
+	sm.State("done")
+
+	sm.Event("start").To("processing").From("pending")
+	sm.Event("finish").To("done").From("processing")
+
+	return sm
+}
+
+func queryState(async *transition.AsyncStateMachine, obj interface{}) string {
+	result, err := async.Query(obj, func(obj interface{}) interface{} {
+		return obj.(transition.Stater).GetState()
+	})
+	if err != nil {
+		return ""
+	}
+	return result.(string)
+}
+
+func TestAsyncStateMachineSelfTransitionsOnFollowUp(t *testing.T) {
+	sm := getAsyncStateMachine()
+	async := transition.NewAsyncStateMachine(sm, 4)
+	async.OnEnter("processing", func(ctx context.Context, obj interface{}) (string, error) {
+		return "finish", nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go async.Run(ctx)
+	defer async.Stop()
+
+	order := &Order{}
+	async.Schedule(ctx, "start", order)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if queryState(async, order) == "done" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := order.GetState(); got != "done" {
+		t.Errorf("expected state done, got %s", got)
+	}
+}
+
+func TestAsyncStateMachineFollowUpDoesNotBlockOnFullQueue(t *testing.T) {
+	sm := getAsyncStateMachine()
+	async := transition.NewAsyncStateMachine(sm, 0)
+	async.OnEnter("processing", func(ctx context.Context, obj interface{}) (string, error) {
+		return "finish", nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go async.Run(ctx)
+	defer async.Stop()
+
+	order := &Order{}
+	async.Schedule(ctx, "start", order)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if queryState(async, order) == "done" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := order.GetState(); got != "done" {
+		t.Errorf("expected state done, got %s", got)
+	}
+}
+
+func TestAsyncStateMachineFollowUpCycleStopsOnContextCancel(t *testing.T) {
+	sm := transition.New()
+	sm.Initial("a")
+	sm.State("b")
+	sm.Event("toB").To("b").From("a")
+	sm.Event("toA").To("a").From("b")
+
+	async := transition.NewAsyncStateMachine(sm, 4)
+	async.OnEnter("a", func(ctx context.Context, obj interface{}) (string, error) {
+		return "toB", nil
+	})
+	async.OnEnter("b", func(ctx context.Context, obj interface{}) (string, error) {
+		return "toA", nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		async.Run(ctx)
+	}()
+
+	order := &Order{}
+	async.Schedule(context.Background(), "toB", order)
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-runDone:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after ctx was cancelled while chasing a follow-up cycle")
+	}
+}
+
+func TestAsyncStateMachineQueryFailsFastAfterStop(t *testing.T) {
+	sm := getAsyncStateMachine()
+	async := transition.NewAsyncStateMachine(sm, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go async.Run(ctx)
+	cancel()
+	async.Stop()
+
+	order := &Order{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := async.Query(order, func(obj interface{}) interface{} { return nil })
+		if err != transition.ErrAsyncStopped {
+			t.Errorf("expected ErrAsyncStopped, got %v", err)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Query did not return after Run exited")
+	}
+}
+
+func TestAsyncStateMachineQueryReflectsAppliedSchedule(t *testing.T) {
+	sm := getAsyncStateMachine()
+	async := transition.NewAsyncStateMachine(sm, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go async.Run(ctx)
+	defer async.Stop()
+
+	order := &Order{}
+	async.Schedule(ctx, "start", order)
+
+	deadline := time.Now().Add(time.Second)
+	var state string
+	for time.Now().Before(deadline) {
+		state = queryState(async, order)
+		if state == "processing" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if state != "processing" {
+		t.Errorf("expected state processing, got %s", state)
+	}
+}
@@ -0,0 +1,155 @@
+package transition
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ToDOT renders the StateMachine as Graphviz DOT. The initial state is
+// drawn bold, terminal states (no outgoing events) as a double circle,
+// guarded transitions are labelled with a "[guard]" suffix, and states with
+// registered Enter/Exit callbacks get an "on_enter"/"on_exit" label.
+func (sm *StateMachine) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph StateMachine {\n")
+	b.WriteString("\trankdir=LR;\n")
+
+	for _, name := range sm.sortedStateNames() {
+		shape := "shape=circle"
+		if sm.isTerminal(name) {
+			shape = "shape=doublecircle"
+		}
+		attrs := []string{shape}
+		if name == sm.initial {
+			attrs = append(attrs, "style=bold")
+		}
+		if suffix := callbackLabel(sm.states[name]); suffix != "" {
+			attrs = append(attrs, fmt.Sprintf(`label="%s\n%s"`, name, suffix))
+		}
+		fmt.Fprintf(&b, "\t%q [%s];\n", name, strings.Join(attrs, ", "))
+	}
+
+	for _, name := range sm.sortedEventNames() {
+		for _, rule := range sm.events[name].rules {
+			label := transitionLabel(name, rule)
+			for _, from := range rule.froms {
+				fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", from, rule.to, label)
+			}
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ToMermaid renders the StateMachine as a Mermaid stateDiagram-v2. The
+// initial state is drawn from [*], terminal states transition back to
+// [*], guarded transitions are labelled with a "[guard]" suffix, and
+// states with registered Enter/Exit callbacks get an annotating note.
+func (sm *StateMachine) ToMermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+
+	if sm.initial != "" {
+		fmt.Fprintf(&b, "\t[*] --> %s\n", sm.initial)
+	}
+
+	for _, name := range sm.sortedStateNames() {
+		if suffix := callbackLabel(sm.states[name]); suffix != "" {
+			fmt.Fprintf(&b, "\tnote right of %s: %s\n", name, strings.ReplaceAll(suffix, "\\n", ", "))
+		}
+	}
+
+	for _, name := range sm.sortedEventNames() {
+		for _, rule := range sm.events[name].rules {
+			label := transitionLabel(name, rule)
+			for _, from := range rule.froms {
+				fmt.Fprintf(&b, "\t%s --> %s: %s\n", from, rule.to, label)
+			}
+		}
+	}
+
+	for _, name := range sm.sortedStateNames() {
+		if sm.isTerminal(name) {
+			fmt.Fprintf(&b, "\t%s --> [*]\n", name)
+		}
+	}
+
+	return b.String()
+}
+
+// WriteGraph writes the StateMachine to w in the given format, either
+// "dot" or "mermaid".
+func (sm *StateMachine) WriteGraph(w io.Writer, format string) error {
+	switch format {
+	case "dot":
+		_, err := io.WriteString(w, sm.ToDOT())
+		return err
+	case "mermaid":
+		_, err := io.WriteString(w, sm.ToMermaid())
+		return err
+	default:
+		return fmt.Errorf("transition: unknown graph format %q", format)
+	}
+}
+
+// transitionLabel describes event, flagging candidates guarded behind a
+// Guard so the rendered graph doesn't imply an unconditional transition.
+func transitionLabel(event string, rule *transitionRule) string {
+	if rule.guard != nil {
+		return event + " [guard]"
+	}
+	return event
+}
+
+// callbackLabel describes state's registered Enter/Exit callbacks, joined
+// with a literal "\n" for embedding in a DOT label, or "" if it has
+// neither.
+func callbackLabel(state *State) string {
+	if state == nil {
+		return ""
+	}
+	var parts []string
+	if len(state.enterFuncs) > 0 {
+		parts = append(parts, "on_enter")
+	}
+	if len(state.exitFuncs) > 0 {
+		parts = append(parts, "on_exit")
+	}
+	return strings.Join(parts, "\\n")
+}
+
+func (sm *StateMachine) sortedStateNames() []string {
+	names := make([]string, 0, len(sm.states))
+	for name := range sm.states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (sm *StateMachine) sortedEventNames() []string {
+	names := make([]string, 0, len(sm.events))
+	for name := range sm.events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isTerminal reports whether no event can ever fire from state, i.e. state
+// (or an ancestor of it) never appears as a From candidate.
+func (sm *StateMachine) isTerminal(state string) bool {
+	for _, ev := range sm.events {
+		for _, rule := range ev.rules {
+			for _, from := range rule.froms {
+				if sm.isWithin(state, from) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package transition_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestToDOTMarksInitialAndTerminalStates(t *testing.T) {
+	sm := getStateMachine()
+	dot := sm.ToDOT()
+
+	if !strings.Contains(dot, "digraph StateMachine {") {
+		t.Errorf("expected a digraph header, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"draft" [shape=circle, style=bold]`) {
+		t.Errorf("expected draft to be marked initial, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"paid" [shape=doublecircle]`) {
+		t.Errorf("expected paid to be marked terminal, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"draft" -> "checkout" [label="checkout"];`) {
+		t.Errorf("expected a checkout transition edge, got:\n%s", dot)
+	}
+}
+
+func TestToMermaidMarksInitialAndTerminalStates(t *testing.T) {
+	sm := getStateMachine()
+	mermaid := sm.ToMermaid()
+
+	if !strings.Contains(mermaid, "stateDiagram-v2") {
+		t.Errorf("expected a stateDiagram-v2 header, got:\n%s", mermaid)
+	}
+	if !strings.Contains(mermaid, "[*] --> draft") {
+		t.Errorf("expected draft to be the initial state, got:\n%s", mermaid)
+	}
+	if !strings.Contains(mermaid, "checkout --> paid: pay") {
+		t.Errorf("expected a pay transition, got:\n%s", mermaid)
+	}
+	if !strings.Contains(mermaid, "paid --> [*]") {
+		t.Errorf("expected paid to be marked terminal, got:\n%s", mermaid)
+	}
+}
+
+func TestToDOTAndMermaidAnnotateCallbacks(t *testing.T) {
+	sm := getStateMachine()
+	sm.State("checkout").Enter(func(ctx context.Context, obj interface{}, args ...interface{}) error {
+		return nil
+	})
+
+	dot := sm.ToDOT()
+	if !strings.Contains(dot, `"checkout" [shape=circle, label="checkout\non_enter"];`) {
+		t.Errorf("expected checkout to be labelled on_enter, got:\n%s", dot)
+	}
+
+	mermaid := sm.ToMermaid()
+	if !strings.Contains(mermaid, "note right of checkout: on_enter") {
+		t.Errorf("expected a checkout on_enter note, got:\n%s", mermaid)
+	}
+}
+
+func TestWriteGraphRejectsUnknownFormat(t *testing.T) {
+	sm := getStateMachine()
+	var buf bytes.Buffer
+	if err := sm.WriteGraph(&buf, "svg"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestWriteGraphWritesDOT(t *testing.T) {
+	sm := getStateMachine()
+	var buf bytes.Buffer
+	if err := sm.WriteGraph(&buf, "dot"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != sm.ToDOT() {
+		t.Errorf("WriteGraph(dot) did not match ToDOT()")
+	}
+}
@@ -0,0 +1,85 @@
+package transition_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/transition"
+)
+
+type Payment struct {
+	transition.Transition
+
+	Amount int
+}
+
+func getPayStateMachine() *transition.StateMachine {
+	sm := transition.New()
+
+	sm.Initial("checkout")
+	sm.State("paid")
+	sm.State("free")
+
+	sm.Event("pay").
+		To("paid").From("checkout").Guard(func(ctx context.Context, obj interface{}) (bool, error) {
+		return obj.(*Payment).Amount > 0, nil
+	}).
+		To("free").From("checkout")
+
+	return sm
+}
+
+func TestGuardPicksFirstPermittedCandidate(t *testing.T) {
+	sm := getPayStateMachine()
+	ctx := context.TODO()
+
+	paid := &Payment{Amount: 100}
+	if err := sm.Trigger(ctx, "pay", paid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if paid.GetState() != "paid" {
+		t.Errorf("expected state paid, got %s", paid.GetState())
+	}
+
+	free := &Payment{Amount: 0}
+	if err := sm.Trigger(ctx, "pay", free); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if free.GetState() != "free" {
+		t.Errorf("expected state free, got %s", free.GetState())
+	}
+}
+
+func TestGuardNoPermittedTransition(t *testing.T) {
+	sm := transition.New()
+	sm.Initial("checkout")
+	sm.State("paid")
+
+	sm.Event("pay").To("paid").From("checkout").Guard(func(ctx context.Context, obj interface{}) (bool, error) {
+		return false, nil
+	})
+
+	order := &Order{}
+	err := sm.Trigger(context.TODO(), "pay", order)
+	if !errors.Is(err, transition.ErrNoPermittedTransition) {
+		t.Errorf("expected ErrNoPermittedTransition, got %v", err)
+	}
+}
+
+func TestGuardPropagatesError(t *testing.T) {
+	sm := transition.New()
+	sm.Initial("checkout")
+	sm.State("paid")
+
+	guardErr := errors.New("guard boom")
+	sm.Event("pay").To("paid").From("checkout").Guard(func(ctx context.Context, obj interface{}) (bool, error) {
+		return false, guardErr
+	})
+
+	order := &Order{}
+	err := sm.Trigger(context.TODO(), "pay", order)
+	if !errors.Is(err, guardErr) {
+		t.Errorf("expected guard error to propagate, got %v", err)
+	}
+}
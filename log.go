@@ -0,0 +1,119 @@
+package transition
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Log records one successful transition.
+type Log struct {
+	From  string
+	To    string
+	Event string
+	At    time.Time
+	Actor string
+	Note  string
+	Args  []interface{}
+}
+
+// LogRecorder is implemented by Transition. Trigger calls RecordLog after
+// every successful transition so StateChanges/LastStateChange can inspect
+// the object's own history without a Store round trip.
+type LogRecorder interface {
+	RecordLog(entry Log)
+}
+
+// Subjecter is implemented by objects that can name themselves for the
+// Store, e.g. an Order returning its Id. Objects that don't implement it
+// are simply not persisted to the Store (in-memory history via
+// StateChanges still works).
+type Subjecter interface {
+	LogSubject() string
+}
+
+// StateChanges returns every transition recorded on this object, oldest
+// first.
+func (t *Transition) StateChanges() []Log {
+	return append([]Log(nil), t.changes...)
+}
+
+// LastStateChange returns the most recent recorded transition, if any.
+func (t *Transition) LastStateChange() (Log, bool) {
+	if len(t.changes) == 0 {
+		return Log{}, false
+	}
+	return t.changes[len(t.changes)-1], true
+}
+
+// RecordLog appends entry to the object's in-memory history.
+func (t *Transition) RecordLog(entry Log) {
+	t.changes = append(t.changes, entry)
+}
+
+// Store persists transition logs outside the process, keyed by the
+// subject returned from Subjecter.LogSubject.
+type Store interface {
+	SaveLog(ctx context.Context, subject string, log Log) error
+	LoadLogs(ctx context.Context, subject string) ([]Log, error)
+}
+
+// MemoryStore is a Store backed by an in-memory map, safe for concurrent
+// use. It is not wired in automatically: a StateMachine persists nothing
+// until WithStore is called with one, e.g. NewMemoryStore().
+type MemoryStore struct {
+	mu   sync.Mutex
+	logs map[string][]Log
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{logs: make(map[string][]Log)}
+}
+
+// SaveLog appends log to subject's history.
+func (s *MemoryStore) SaveLog(ctx context.Context, subject string, log Log) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[subject] = append(s.logs[subject], log)
+	return nil
+}
+
+// LoadLogs returns subject's history, oldest first.
+func (s *MemoryStore) LoadLogs(ctx context.Context, subject string) ([]Log, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Log(nil), s.logs[subject]...), nil
+}
+
+// TriggerOption configures a single Trigger call, e.g. who performed it and
+// why. Options are passed alongside event args and are not forwarded to
+// Before/Exit/Enter/After callbacks.
+type TriggerOption func(*triggerOptions)
+
+type triggerOptions struct {
+	actor string
+	note  string
+}
+
+// WithActor records who performed the transition.
+func WithActor(id string) TriggerOption {
+	return func(o *triggerOptions) {
+		o.actor = id
+	}
+}
+
+// WithNote attaches a free-form note to the transition log.
+func WithNote(note string) TriggerOption {
+	return func(o *triggerOptions) {
+		o.note = note
+	}
+}
+
+// WithStore configures the Store that successful transitions are persisted
+// to, keyed by Subjecter.LogSubject. Objects that don't implement
+// Subjecter are skipped.
+func (sm *StateMachine) WithStore(store Store) *StateMachine {
+	sm.store = store
+	return sm
+}
@@ -0,0 +1,63 @@
+package transition_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alextanhongpin/transition"
+)
+
+type LoggedOrder struct {
+	Id int
+
+	transition.Transition
+}
+
+func (o *LoggedOrder) LogSubject() string {
+	return "order:1"
+}
+
+func TestStateChangesRecordedOnTransition(t *testing.T) {
+	sm := getStateMachine()
+	order := &LoggedOrder{Id: 1}
+	ctx := context.TODO()
+
+	if err := sm.Trigger(ctx, "checkout", order, transition.WithActor("alice"), transition.WithNote("first order")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changes := order.StateChanges()
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 recorded change, got %d", len(changes))
+	}
+
+	last, ok := order.LastStateChange()
+	if !ok {
+		t.Fatal("expected a last state change")
+	}
+	if last.From != "draft" || last.To != "checkout" || last.Event != "checkout" {
+		t.Errorf("unexpected log entry: %+v", last)
+	}
+	if last.Actor != "alice" || last.Note != "first order" {
+		t.Errorf("WithActor/WithNote not applied: %+v", last)
+	}
+}
+
+func TestWithStorePersistsLog(t *testing.T) {
+	store := transition.NewMemoryStore()
+	sm := getStateMachine().WithStore(store)
+	order := &LoggedOrder{Id: 1}
+	ctx := context.TODO()
+
+	if err := sm.Trigger(ctx, "checkout", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs, err := store.LoadLogs(ctx, "order:1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 1 || logs[0].To != "checkout" {
+		t.Errorf("expected persisted log for order:1, got %+v", logs)
+	}
+}
@@ -0,0 +1,41 @@
+package transition
+
+// Registry resolves the callback and guard names referenced by a
+// declarative machine definition (see LoadFromJSON/LoadFromYAML) to the Go
+// functions that actually implement them.
+type Registry struct {
+	callbacks map[string]CallbackFunc
+	guards    map[string]GuardFunc
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		callbacks: make(map[string]CallbackFunc),
+		guards:    make(map[string]GuardFunc),
+	}
+}
+
+// RegisterCallback makes fn resolvable under name from an on_enter/on_exit
+// field in a loaded schema.
+func (r *Registry) RegisterCallback(name string, fn CallbackFunc) *Registry {
+	r.callbacks[name] = fn
+	return r
+}
+
+// RegisterGuard makes fn resolvable under name from a guard field in a
+// loaded schema.
+func (r *Registry) RegisterGuard(name string, fn GuardFunc) *Registry {
+	r.guards[name] = fn
+	return r
+}
+
+func (r *Registry) callback(name string) (CallbackFunc, bool) {
+	fn, ok := r.callbacks[name]
+	return fn, ok
+}
+
+func (r *Registry) guard(name string) (GuardFunc, bool) {
+	fn, ok := r.guards[name]
+	return fn, ok
+}
@@ -0,0 +1,106 @@
+package transition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// machineSchema is the declarative shape accepted by LoadFromJSON and
+// LoadFromYAML.
+type machineSchema struct {
+	Initial string        `json:"initial" yaml:"initial"`
+	States  []stateSchema `json:"states" yaml:"states"`
+	Events  []eventSchema `json:"events" yaml:"events"`
+}
+
+type stateSchema struct {
+	Name    string `json:"name" yaml:"name"`
+	Parent  string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	OnEnter string `json:"on_enter,omitempty" yaml:"on_enter,omitempty"`
+	OnExit  string `json:"on_exit,omitempty" yaml:"on_exit,omitempty"`
+}
+
+type eventSchema struct {
+	Name        string             `json:"name" yaml:"name"`
+	Transitions []transitionSchema `json:"transitions" yaml:"transitions"`
+}
+
+type transitionSchema struct {
+	From  []string `json:"from" yaml:"from"`
+	To    string   `json:"to" yaml:"to"`
+	Guard string   `json:"guard,omitempty" yaml:"guard,omitempty"`
+}
+
+// LoadFromJSON parses a declarative machine definition and wires its
+// on_enter/on_exit callbacks and guards from registry. The result is run
+// through Validate before it is returned.
+func LoadFromJSON(data []byte, registry *Registry) (*StateMachine, error) {
+	var schema machineSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("transition: decode JSON: %w", err)
+	}
+	return buildFromSchema(schema, registry)
+}
+
+// LoadFromYAML is the YAML equivalent of LoadFromJSON, for teams who keep
+// workflow definitions in version-controlled YAML rather than Go code.
+func LoadFromYAML(data []byte, registry *Registry) (*StateMachine, error) {
+	var schema machineSchema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("transition: decode YAML: %w", err)
+	}
+	return buildFromSchema(schema, registry)
+}
+
+func buildFromSchema(schema machineSchema, registry *Registry) (*StateMachine, error) {
+	if schema.Initial == "" {
+		return nil, errors.New("transition: schema has no initial state")
+	}
+
+	sm := New()
+	sm.Initial(schema.Initial)
+
+	for _, s := range schema.States {
+		state := sm.State(s.Name)
+		if s.Parent != "" {
+			state.SubstateOf(s.Parent)
+		}
+		if s.OnEnter != "" {
+			fn, ok := registry.callback(s.OnEnter)
+			if !ok {
+				return nil, fmt.Errorf("transition: state %q: unregistered on_enter callback %q", s.Name, s.OnEnter)
+			}
+			state.Enter(fn)
+		}
+		if s.OnExit != "" {
+			fn, ok := registry.callback(s.OnExit)
+			if !ok {
+				return nil, fmt.Errorf("transition: state %q: unregistered on_exit callback %q", s.Name, s.OnExit)
+			}
+			state.Exit(fn)
+		}
+	}
+
+	for _, e := range schema.Events {
+		event := sm.Event(e.Name)
+		for _, tr := range e.Transitions {
+			event.To(tr.To).From(tr.From...)
+			if tr.Guard != "" {
+				fn, ok := registry.guard(tr.Guard)
+				if !ok {
+					return nil, fmt.Errorf("transition: event %q: unregistered guard %q", e.Name, tr.Guard)
+				}
+				event.Guard(fn)
+			}
+		}
+	}
+
+	if errs := sm.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("transition: invalid machine definition: %w", errors.Join(errs...))
+	}
+
+	return sm, nil
+}
@@ -0,0 +1,73 @@
+package transition_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alextanhongpin/transition"
+)
+
+const orderSchemaJSON = `{
+	"initial": "draft",
+	"states": [
+		{"name": "draft"},
+		{"name": "checkout", "on_enter": "logCheckout"},
+		{"name": "paid"}
+	],
+	"events": [
+		{"name": "checkout", "transitions": [{"from": ["draft"], "to": "checkout"}]},
+		{"name": "pay", "transitions": [{"from": ["checkout"], "to": "paid", "guard": "hasAmount"}]}
+	]
+}`
+
+func TestLoadFromJSONBuildsWorkingMachine(t *testing.T) {
+	var entered bool
+	registry := transition.NewRegistry()
+	registry.RegisterCallback("logCheckout", func(ctx context.Context, obj interface{}, args ...interface{}) error {
+		entered = true
+		return nil
+	})
+	registry.RegisterGuard("hasAmount", func(ctx context.Context, obj interface{}) (bool, error) {
+		return true, nil
+	})
+
+	sm, err := transition.LoadFromJSON([]byte(orderSchemaJSON), registry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order := &Order{}
+	ctx := context.TODO()
+	if err := sm.Trigger(ctx, "checkout", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !entered {
+		t.Error("expected registered on_enter callback to run")
+	}
+
+	if err := sm.Trigger(ctx, "pay", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.GetState() != "paid" {
+		t.Errorf("expected state paid, got %s", order.GetState())
+	}
+}
+
+func TestLoadFromJSONUnregisteredCallback(t *testing.T) {
+	_, err := transition.LoadFromJSON([]byte(orderSchemaJSON), transition.NewRegistry())
+	if err == nil {
+		t.Fatal("expected an error for an unregistered on_enter callback")
+	}
+}
+
+func TestLoadFromJSONRejectsUnreachableState(t *testing.T) {
+	schema := `{
+		"initial": "draft",
+		"states": [{"name": "draft"}, {"name": "orphan"}],
+		"events": []
+	}`
+	_, err := transition.LoadFromJSON([]byte(schema), transition.NewRegistry())
+	if err == nil {
+		t.Fatal("expected Validate to reject the unreachable orphan state")
+	}
+}
@@ -0,0 +1,72 @@
+package transition
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// SQLStore is a Store backed by a SQL database. It expects a table created
+// with roughly the following schema:
+//
+//	CREATE TABLE transition_logs (
+//		subject    TEXT NOT NULL,
+//		from_state TEXT NOT NULL,
+//		to_state   TEXT NOT NULL,
+//		event      TEXT NOT NULL,
+//		at         TIMESTAMP NOT NULL,
+//		actor      TEXT NOT NULL,
+//		note       TEXT NOT NULL,
+//		args       TEXT NOT NULL
+//	);
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore creates a SQLStore that reads and writes table through db.
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	return &SQLStore{db: db, table: table}
+}
+
+// SaveLog inserts log as a row for subject. Args is marshalled to JSON.
+func (s *SQLStore) SaveLog(ctx context.Context, subject string, log Log) error {
+	args, err := json.Marshal(log.Args)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO `+s.table+` (subject, from_state, to_state, event, at, actor, note, args)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		subject, log.From, log.To, log.Event, log.At, log.Actor, log.Note, args,
+	)
+	return err
+}
+
+// LoadLogs returns subject's history, oldest first.
+func (s *SQLStore) LoadLogs(ctx context.Context, subject string) ([]Log, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT from_state, to_state, event, at, actor, note, args
+		 FROM `+s.table+` WHERE subject = ? ORDER BY at ASC`,
+		subject,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []Log
+	for rows.Next() {
+		var log Log
+		var args []byte
+		if err := rows.Scan(&log.From, &log.To, &log.Event, &log.At, &log.Actor, &log.Note, &args); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(args, &log.Args); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
@@ -0,0 +1,113 @@
+package transition_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alextanhongpin/transition"
+)
+
+func getPaymentStateMachine() *transition.StateMachine {
+	sm := transition.New()
+
+	sm.Initial("draft")
+	sm.State("checkout")
+	sm.State("paid")
+	sm.State("processed").SubstateOf("paid")
+	sm.State("delivered").SubstateOf("paid")
+
+	sm.Event("checkout").To("checkout").From("draft")
+	sm.Event("pay").To("paid").From("checkout")
+	sm.Event("process").To("processed").From("paid")
+	sm.Event("deliver").To("delivered").From("paid")
+	sm.Event("refund").To("checkout").From("paid")
+
+	return sm
+}
+
+func TestSubstateEntersParentOnce(t *testing.T) {
+	var entered []string
+	sm := getPaymentStateMachine()
+	sm.State("paid").Enter(func(ctx context.Context, obj interface{}, args ...interface{}) error {
+		entered = append(entered, "paid")
+		return nil
+	})
+
+	order := &Order{}
+	ctx := context.TODO()
+
+	if err := sm.Trigger(ctx, "checkout", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sm.Trigger(ctx, "pay", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sm.Trigger(ctx, "process", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if order.GetState() != "processed" {
+		t.Errorf("expected state processed, got %s", order.GetState())
+	}
+
+	if len(entered) != 1 {
+		t.Errorf("expected paid.Enter to run once, ran %d times: %v", len(entered), entered)
+	}
+}
+
+func TestSubstateEventFromParentMatchesDescendant(t *testing.T) {
+	sm := getPaymentStateMachine()
+	order := &Order{}
+	ctx := context.TODO()
+
+	if err := sm.Trigger(ctx, "checkout", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sm.Trigger(ctx, "pay", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sm.Trigger(ctx, "deliver", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := sm.Trigger(ctx, "refund", order); err != nil {
+		t.Errorf("refund should match delivered via its paid ancestor: %v", err)
+	}
+
+	if order.GetState() != "checkout" {
+		t.Errorf("expected state checkout, got %s", order.GetState())
+	}
+}
+
+func TestSubstateExitsChildBeforeParent(t *testing.T) {
+	var exited []string
+	sm := getPaymentStateMachine()
+	sm.State("processed").Exit(func(ctx context.Context, obj interface{}, args ...interface{}) error {
+		exited = append(exited, "processed")
+		return nil
+	})
+	sm.State("paid").Exit(func(ctx context.Context, obj interface{}, args ...interface{}) error {
+		exited = append(exited, "paid")
+		return nil
+	})
+
+	order := &Order{}
+	ctx := context.TODO()
+
+	if err := sm.Trigger(ctx, "checkout", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sm.Trigger(ctx, "pay", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sm.Trigger(ctx, "process", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sm.Trigger(ctx, "refund", order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(exited) != 2 || exited[0] != "processed" || exited[1] != "paid" {
+		t.Errorf("expected child-first exit order [processed paid], got %v", exited)
+	}
+}
@@ -0,0 +1,446 @@
+// Package transition implements a lightweight finite state machine for Go
+// structs. States and events are registered on a StateMachine, and structs
+// track their current state by embedding Transition.
+package transition
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrEventNotFound is returned when Trigger is called with an event that has
+// not been registered on the StateMachine.
+var ErrEventNotFound = errors.New("transition: event not found")
+
+// ErrNoPermittedTransition is returned when none of an event's candidates
+// match the object's current state, or every matching candidate's Guard
+// rejected the transition.
+var ErrNoPermittedTransition = errors.New("transition: no permitted transition")
+
+// CallbackFunc is invoked before/after an event and on entering/exiting a
+// state. args carries whatever was passed to Trigger, letting events like
+// pay(amount, method) hand typed data to their handlers. Returning a
+// non-nil error aborts the transition and leaves the object in its prior
+// state; an After error rolls back a state change already made.
+type CallbackFunc func(ctx context.Context, obj interface{}, args ...interface{}) error
+
+// AroundFunc wraps a state or event transition. It must call next to let the
+// transition proceed; not calling it aborts the transition without an error.
+// This is where callers hook in timing, tracing spans, DB transactions, or
+// retry logic around the inner Before/Exit/Enter/After callbacks.
+type AroundFunc func(ctx context.Context, obj interface{}, next func() error) error
+
+// runAround composes arounds, outermost first, around inner.
+func runAround(arounds []AroundFunc, ctx context.Context, obj interface{}, inner func() error) error {
+	fn := inner
+	for i := len(arounds) - 1; i >= 0; i-- {
+		around, next := arounds[i], fn
+		fn = func() error { return around(ctx, obj, next) }
+	}
+	return fn()
+}
+
+// Stater is implemented by structs that embed Transition. It lets the
+// StateMachine read and write the current state without depending on the
+// concrete struct type.
+type Stater interface {
+	GetState() string
+	SetState(state string)
+}
+
+// Transition is meant to be embedded in structs that are managed by a
+// StateMachine. It tracks the struct's current state.
+type Transition struct {
+	State string
+
+	changes []Log
+}
+
+// GetState returns the current state.
+func (t *Transition) GetState() string {
+	return t.State
+}
+
+// SetState sets the current state.
+func (t *Transition) SetState(state string) {
+	t.State = state
+}
+
+// State represents a single state in a StateMachine, with optional callbacks
+// that run when the state is entered or exited.
+type State struct {
+	name       string
+	parent     string
+	enterFuncs []CallbackFunc
+	exitFuncs  []CallbackFunc
+	arounds    []AroundFunc
+}
+
+// Enter registers a callback that runs whenever this state is entered.
+func (s *State) Enter(fn CallbackFunc) *State {
+	s.enterFuncs = append(s.enterFuncs, fn)
+	return s
+}
+
+// Exit registers a callback that runs whenever this state is exited.
+func (s *State) Exit(fn CallbackFunc) *State {
+	s.exitFuncs = append(s.exitFuncs, fn)
+	return s
+}
+
+// Around registers a callback that wraps this state's Enter callbacks when
+// it is entered, and its Exit callbacks when it is exited.
+func (s *State) Around(fn AroundFunc) *State {
+	s.arounds = append(s.arounds, fn)
+	return s
+}
+
+// SubstateOf marks this state as a child of parent, so it inherits the
+// parent's Enter/Exit callbacks. Entering any descendant of parent invokes
+// parent's Enter callbacks (outermost-first) unless the object is already
+// within parent; leaving it invokes Exit callbacks child-first, up to the
+// least common ancestor of the old and new state.
+func (s *State) SubstateOf(parent string) *State {
+	s.parent = parent
+	return s
+}
+
+// GuardFunc decides whether a transitionRule may be taken. It is evaluated
+// only once the rule's From has matched the object's current state.
+type GuardFunc func(ctx context.Context, obj interface{}) (bool, error)
+
+// transitionRule describes one From/To candidate belonging to an event.
+type transitionRule struct {
+	to    string
+	froms []string
+	guard GuardFunc
+}
+
+// EventTransition represents an event that can move an object from one or
+// more states to a target state.
+type EventTransition struct {
+	name        string
+	sm          *StateMachine
+	rules       []*transitionRule
+	beforeFuncs []CallbackFunc
+	afterFuncs  []CallbackFunc
+	arounds     []AroundFunc
+}
+
+// To declares the target state for the transition currently being built and
+// starts a new rule. Call From on the result to declare which states the
+// event is permitted from.
+func (e *EventTransition) To(state string) *EventTransition {
+	e.rules = append(e.rules, &transitionRule{to: state})
+	return e
+}
+
+// From declares the states the most recently declared To is permitted from.
+func (e *EventTransition) From(states ...string) *EventTransition {
+	if len(e.rules) == 0 {
+		return e
+	}
+	rule := e.rules[len(e.rules)-1]
+	rule.froms = append(rule.froms, states...)
+	return e
+}
+
+// Guard attaches a predicate to the most recently declared To/From
+// candidate. When an event has several candidates matching the current
+// state, Trigger picks the first one, in registration order, whose guard
+// either is nil or returns true.
+func (e *EventTransition) Guard(fn GuardFunc) *EventTransition {
+	if len(e.rules) == 0 {
+		return e
+	}
+	e.rules[len(e.rules)-1].guard = fn
+	return e
+}
+
+// Before registers a callback that runs before the state changes, once the
+// event's current state has matched a rule.
+func (e *EventTransition) Before(fn CallbackFunc) *EventTransition {
+	e.beforeFuncs = append(e.beforeFuncs, fn)
+	return e
+}
+
+// After registers a callback that runs after the state has changed.
+func (e *EventTransition) After(fn CallbackFunc) *EventTransition {
+	e.afterFuncs = append(e.afterFuncs, fn)
+	return e
+}
+
+// Around registers a callback that wraps the entire transition: every
+// Before/Exit/Enter/After callback runs inside it, in registration order
+// with the first registered Around outermost.
+func (e *EventTransition) Around(fn AroundFunc) *EventTransition {
+	e.arounds = append(e.arounds, fn)
+	return e
+}
+
+// match evaluates, in registration order, every candidate whose From
+// matches the given state (a rule registered From(parent) also matches any
+// descendant of parent) and returns the first one whose Guard passes. A
+// candidate with no Guard always passes.
+func (e *EventTransition) match(ctx context.Context, obj interface{}, from string) (*transitionRule, error) {
+	for _, rule := range e.rules {
+		matched := false
+		for _, f := range rule.froms {
+			if e.sm.isWithin(from, f) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if rule.guard == nil {
+			return rule, nil
+		}
+		ok, err := rule.guard(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return rule, nil
+		}
+	}
+	return nil, ErrNoPermittedTransition
+}
+
+// StateMachine holds the registered states and events for a workflow.
+type StateMachine struct {
+	initial        string
+	initialHistory []string
+	states         map[string]*State
+	events         map[string]*EventTransition
+	store          Store
+}
+
+// New creates an empty StateMachine.
+func New() *StateMachine {
+	return &StateMachine{
+		states: make(map[string]*State),
+		events: make(map[string]*EventTransition),
+	}
+}
+
+// Initial registers and sets the state new objects start in. Calling it
+// more than once is recorded so Validate can flag the ambiguity.
+func (sm *StateMachine) Initial(name string) *StateMachine {
+	sm.initial = name
+	sm.initialHistory = append(sm.initialHistory, name)
+	sm.State(name)
+	return sm
+}
+
+// State registers name if it hasn't been seen yet, and returns it so
+// callbacks can be attached.
+func (sm *StateMachine) State(name string) *State {
+	s, ok := sm.states[name]
+	if !ok {
+		s = &State{name: name}
+		sm.states[name] = s
+	}
+	return s
+}
+
+// Event registers name if it hasn't been seen yet, and returns it so
+// transitions and callbacks can be attached.
+func (sm *StateMachine) Event(name string) *EventTransition {
+	e, ok := sm.events[name]
+	if !ok {
+		e = &EventTransition{name: name, sm: sm}
+		sm.events[name] = e
+	}
+	return e
+}
+
+// chain returns name together with its ancestors, ordered from name up to
+// the root of its substate tree.
+func (sm *StateMachine) chain(name string) []string {
+	var chain []string
+	for name != "" {
+		chain = append(chain, name)
+		s, ok := sm.states[name]
+		if !ok {
+			break
+		}
+		name = s.parent
+	}
+	return chain
+}
+
+// isWithin reports whether state is ancestor, or a descendant of it.
+func (sm *StateMachine) isWithin(state, ancestor string) bool {
+	for _, s := range sm.chain(state) {
+		if s == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Trigger fires event on obj, threading args into every Before/Exit/Enter/
+// After callback. obj must embed Transition. The state is changed before
+// After runs, so After can observe it, but if any After callback errors
+// the state is rolled back to the state the transition started from, same
+// as a Before/Exit/Enter failure. An obj with no state yet is placed in
+// the initial state first, so that is the state it is left in. Any Around callbacks registered on the
+// event or on the states involved wrap the corresponding step.
+//
+// args may include TriggerOption values such as WithActor and WithNote;
+// these configure the transition's Log entry and are not forwarded to
+// callbacks. On success, the Log is recorded on obj's embedded Transition
+// and, if a Store is configured and obj implements Subjecter, persisted
+// there too.
+func (sm *StateMachine) Trigger(ctx context.Context, event string, obj interface{}, args ...interface{}) error {
+	stater, ok := obj.(Stater)
+	if !ok {
+		return errors.New("transition: obj does not implement Stater")
+	}
+
+	var opts triggerOptions
+	callbackArgs := make([]interface{}, 0, len(args))
+	for _, a := range args {
+		if opt, ok := a.(TriggerOption); ok {
+			opt(&opts)
+			continue
+		}
+		callbackArgs = append(callbackArgs, a)
+	}
+
+	current := stater.GetState()
+	if current == "" {
+		current = sm.initial
+		stater.SetState(current)
+	}
+
+	ev, ok := sm.events[event]
+	if !ok {
+		return ErrEventNotFound
+	}
+
+	rule, err := ev.match(ctx, obj, current)
+	if err != nil {
+		return err
+	}
+
+	// Find the least common ancestor between the current and target state
+	// trees: callbacks are only run for states strictly below it, so shared
+	// ancestors that are already active are neither exited nor re-entered.
+	currentChain := sm.chain(current)
+	targetChain := sm.chain(rule.to)
+	lca := ""
+	for _, c := range currentChain {
+		for _, t := range targetChain {
+			if c == t {
+				lca = c
+			}
+		}
+		if lca != "" {
+			break
+		}
+	}
+
+	var toEnter []string
+	for _, name := range targetChain {
+		if name == lca {
+			break
+		}
+		toEnter = append(toEnter, name)
+	}
+
+	applied := false
+	inner := func() error {
+		for _, fn := range ev.beforeFuncs {
+			if err := fn(ctx, obj, callbackArgs...); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range currentChain {
+			if name == lca {
+				break
+			}
+			s, ok := sm.states[name]
+			if !ok {
+				continue
+			}
+			if err := runAround(s.arounds, ctx, obj, func() error {
+				for _, fn := range s.exitFuncs {
+					if err := fn(ctx, obj, callbackArgs...); err != nil {
+						return err
+					}
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		for i := len(toEnter) - 1; i >= 0; i-- {
+			s, ok := sm.states[toEnter[i]]
+			if !ok {
+				continue
+			}
+			if err := runAround(s.arounds, ctx, obj, func() error {
+				for _, fn := range s.enterFuncs {
+					if err := fn(ctx, obj, callbackArgs...); err != nil {
+						return err
+					}
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		stater.SetState(rule.to)
+
+		for _, fn := range ev.afterFuncs {
+			if err := fn(ctx, obj, callbackArgs...); err != nil {
+				stater.SetState(current)
+				return err
+			}
+		}
+
+		applied = true
+		return nil
+	}
+
+	if err := runAround(ev.arounds, ctx, obj, inner); err != nil {
+		return err
+	}
+
+	// An Around that skipped next (or swallowed its error) leaves nothing
+	// to log.
+	if !applied {
+		return nil
+	}
+
+	entry := Log{
+		From:  current,
+		To:    rule.to,
+		Event: event,
+		At:    time.Now(),
+		Actor: opts.actor,
+		Note:  opts.note,
+		Args:  callbackArgs,
+	}
+
+	if recorder, ok := obj.(LogRecorder); ok {
+		recorder.RecordLog(entry)
+	}
+
+	if sm.store != nil {
+		if subj, ok := obj.(Subjecter); ok {
+			if err := sm.store.SaveLog(ctx, subj.LogSubject(), entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -106,10 +106,10 @@ func TestStateCallbacks(t *testing.T) {
 
 	address1 := "I'm an address should be set when enter checkout"
 	address2 := "I'm an address should be set when exit checkout"
-	orderStateMachine.State("checkout").Enter(func(ctx context.Context, order interface{}) error {
+	orderStateMachine.State("checkout").Enter(func(ctx context.Context, order interface{}, args ...interface{}) error {
 		order.(*Order).Address = address1
 		return nil
-	}).Exit(func(ctx context.Context, order interface{}) error {
+	}).Exit(func(ctx context.Context, order interface{}, args ...interface{}) error {
 		order.(*Order).Address = address2
 		return nil
 	})
@@ -139,10 +139,10 @@ func TestEventCallbacks(t *testing.T) {
 		ctx                   = context.TODO()
 	)
 
-	orderStateMachine.Event("checkout").To("checkout").From("draft").Before(func(ctx context.Context, order interface{}) error {
+	orderStateMachine.Event("checkout").To("checkout").From("draft").Before(func(ctx context.Context, order interface{}, args ...interface{}) error {
 		prevState = order.(*Order).State
 		return nil
-	}).After(func(ctx context.Context, order interface{}) error {
+	}).After(func(ctx context.Context, order interface{}, args ...interface{}) error {
 		afterState = order.(*Order).State
 		return nil
 	})
@@ -168,7 +168,7 @@ func TestTransitionOnEnterCallbackError(t *testing.T) {
 		ctx               = context.TODO()
 	)
 
-	orderStateMachine.State("checkout").Enter(func(ctx context.Context, order interface{}) (err error) {
+	orderStateMachine.State("checkout").Enter(func(ctx context.Context, order interface{}, args ...interface{}) (err error) {
 		return errors.New("intentional error")
 	})
 
@@ -188,7 +188,7 @@ func TestTransitionOnExitCallbackError(t *testing.T) {
 		ctx               = context.TODO()
 	)
 
-	orderStateMachine.State("checkout").Exit(func(ctx context.Context, order interface{}) (err error) {
+	orderStateMachine.State("checkout").Exit(func(ctx context.Context, order interface{}, args ...interface{}) (err error) {
 		return errors.New("intentional error")
 	})
 
@@ -212,7 +212,7 @@ func TestEventOnBeforeCallbackError(t *testing.T) {
 		ctx               = context.TODO()
 	)
 
-	orderStateMachine.Event("checkout").To("checkout").From("draft").Before(func(ctx context.Context, order interface{}) error {
+	orderStateMachine.Event("checkout").To("checkout").From("draft").Before(func(ctx context.Context, order interface{}, args ...interface{}) error {
 		return errors.New("intentional error")
 	})
 
@@ -232,7 +232,7 @@ func TestEventOnAfterCallbackError(t *testing.T) {
 		ctx               = context.TODO()
 	)
 
-	orderStateMachine.Event("checkout").To("checkout").From("draft").After(func(ctx context.Context, order interface{}) error {
+	orderStateMachine.Event("checkout").To("checkout").From("draft").After(func(ctx context.Context, order interface{}, args ...interface{}) error {
 		return errors.New("intentional error")
 	})
 
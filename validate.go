@@ -0,0 +1,74 @@
+package transition
+
+import "fmt"
+
+// Validate reports structural problems that Trigger would otherwise only
+// surface at runtime: states referenced by From/To that were never
+// registered, states unreachable from the initial state, and Initial
+// having been called more than once with conflicting values.
+func (sm *StateMachine) Validate() []error {
+	var errs []error
+
+	if len(sm.initialHistory) > 1 {
+		for _, name := range sm.initialHistory[1:] {
+			if name != sm.initialHistory[0] {
+				errs = append(errs, fmt.Errorf("transition: duplicate initial state: %q and %q", sm.initialHistory[0], name))
+			}
+		}
+	}
+
+	for _, name := range sm.sortedEventNames() {
+		for _, rule := range sm.events[name].rules {
+			if _, ok := sm.states[rule.to]; !ok {
+				errs = append(errs, fmt.Errorf("transition: event %q: undefined To state %q", name, rule.to))
+			}
+			for _, from := range rule.froms {
+				if _, ok := sm.states[from]; !ok {
+					errs = append(errs, fmt.Errorf("transition: event %q: undefined From state %q", name, from))
+				}
+			}
+		}
+	}
+
+	for _, name := range sm.sortedStateNames() {
+		if !sm.reachableFromInitial(name) {
+			errs = append(errs, fmt.Errorf("transition: state %q is unreachable from initial state %q", name, sm.initial))
+		}
+	}
+
+	return errs
+}
+
+// reachableFromInitial reports whether state is the initial state, a state
+// reachable from it by following one or more To edges, or a descendant of
+// either.
+func (sm *StateMachine) reachableFromInitial(state string) bool {
+	reached := map[string]bool{sm.initial: true}
+	queue := []string{sm.initial}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == state || sm.isWithin(state, current) {
+			return true
+		}
+
+		for _, ev := range sm.events {
+			for _, rule := range ev.rules {
+				if reached[rule.to] {
+					continue
+				}
+				for _, from := range rule.froms {
+					if sm.isWithin(current, from) {
+						reached[rule.to] = true
+						queue = append(queue, rule.to)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package transition_test
+
+import (
+	"testing"
+
+	"github.com/alextanhongpin/transition"
+)
+
+func TestValidateUndefinedToState(t *testing.T) {
+	sm := transition.New()
+	sm.Initial("draft")
+	sm.Event("checkout").To("checkout").From("draft")
+
+	errs := sm.Validate()
+	if len(errs) == 0 {
+		t.Fatal("expected an error for the undefined To state \"checkout\"")
+	}
+}
+
+func TestValidateUnreachableState(t *testing.T) {
+	sm := transition.New()
+	sm.Initial("draft")
+	sm.State("orphan")
+
+	errs := sm.Validate()
+	if len(errs) == 0 {
+		t.Fatal("expected an error for the unreachable \"orphan\" state")
+	}
+}
+
+func TestValidateDuplicateInitialState(t *testing.T) {
+	sm := transition.New()
+	sm.Initial("draft")
+	sm.Initial("checkout")
+
+	errs := sm.Validate()
+	if len(errs) == 0 {
+		t.Fatal("expected an error for conflicting Initial declarations")
+	}
+}
+
+func TestValidateCleanMachine(t *testing.T) {
+	sm := transition.New()
+	sm.Initial("draft")
+	sm.State("checkout")
+	sm.Event("checkout").To("checkout").From("draft")
+
+	errs := sm.Validate()
+	if len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}